@@ -0,0 +1,160 @@
+package gin_logrus
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedactFunc strips sensitive data (passwords, tokens, PII) from a captured
+// request or response body field before it is attached to the log entry.
+// field is either "http.request.body.content" or "http.response.body.content".
+type RedactFunc func(field string, value []byte) []byte
+
+// BodyLogConfig controls opt-in request/response body capture.
+type BodyLogConfig struct {
+	// Enabled turns body capture on. Defaults to off.
+	Enabled bool
+
+	// MaxBytes caps how many bytes of each body are captured, per
+	// direction. For the request body, 0 means no limit. For the response
+	// body, 0 instead *disables* capture: a streamed response (e.g.
+	// c.SSEvent, a long-lived SSE connection) has no natural end, so
+	// treating 0 as "unlimited" there would buffer the entire stream in
+	// memory for the life of the connection. Set MaxBytes on any config
+	// that needs response bodies captured.
+	MaxBytes int
+
+	// MimeTypes allowlists which content types are captured, e.g.
+	// "application/json", "text/". A prefix match is used, so "text/"
+	// matches "text/plain" and "text/html". An empty list captures every
+	// content type.
+	MimeTypes []string
+
+	// Redact, when non-nil, is applied to every captured body before it is
+	// added to the log fields.
+	Redact RedactFunc
+}
+
+// responseBodyLogWriter wraps gin.ResponseWriter to additionally buffer
+// everything written to the response, up to maxBytes. Flush/Hijack/
+// CloseNotify are promoted straight through to the embedded
+// gin.ResponseWriter, so SSE and websocket upgrades keep working unchanged.
+type responseBodyLogWriter struct {
+	gin.ResponseWriter
+	body     *bytes.Buffer
+	maxBytes int
+}
+
+func (w *responseBodyLogWriter) Write(b []byte) (int, error) {
+	w.buffer(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString buffers s like Write, then delegates to the embedded
+// gin.ResponseWriter. Without this override, gin's string/text renderers
+// (c.String, io.WriteString) would use the promoted WriteString and bypass
+// the capture buffer entirely.
+func (w *responseBodyLogWriter) WriteString(s string) (int, error) {
+	w.buffer([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// buffer appends up to maxBytes total bytes of b to w.body. maxBytes <= 0
+// disables capture outright, rather than meaning "unlimited", so a streamed
+// response (SSE, chunked downloads, ...) is never buffered in full.
+func (w *responseBodyLogWriter) buffer(b []byte) {
+	if w.maxBytes <= 0 {
+		return
+	}
+	if remaining := w.maxBytes - w.body.Len(); remaining > 0 {
+		if remaining > len(b) {
+			w.body.Write(b)
+		} else {
+			w.body.Write(b[:remaining])
+		}
+	}
+}
+
+func mimeAllowed(contentType string, allowlist []string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, allowed := range allowlist {
+		if strings.HasPrefix(contentType, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// captureRequestBody tees the request body into a bounded buffer, restoring
+// c.Request.Body so downstream handlers still see the full, original stream.
+func captureRequestBody(c *gin.Context, cfg BodyLogConfig) *bytes.Buffer {
+	if c.Request.Body == nil || !mimeAllowed(c.ContentType(), cfg.MimeTypes) {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	reader := io.TeeReader(c.Request.Body, &limitedWriter{buf: buf, max: cfg.MaxBytes})
+	c.Request.Body = io.NopCloser(reader)
+
+	return buf
+}
+
+// limitedWriter writes at most max bytes into buf; max <= 0 means no limit,
+// matching BodyLogConfig.MaxBytes for the request body. It never returns an
+// error so it is safe to use as the destination of an io.TeeReader without
+// affecting the original read.
+type limitedWriter struct {
+	buf *bytes.Buffer
+	max int
+}
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	if w.max > 0 {
+		remaining := w.max - w.buf.Len()
+		if remaining <= 0 {
+			return len(b), nil
+		}
+		if remaining < len(b) {
+			b = b[:remaining]
+		}
+	}
+	w.buf.Write(b)
+	return len(b), nil
+}
+
+// captureResponseWriter swaps in a responseBodyLogWriter and returns it so
+// the caller can read back the buffered body once the handler has run.
+func captureResponseWriter(c *gin.Context, cfg BodyLogConfig) *responseBodyLogWriter {
+	writer := &responseBodyLogWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, maxBytes: cfg.MaxBytes}
+	c.Writer = writer
+	return writer
+}
+
+// bodyLogFields builds the http.request.body.content / http.response.body.content
+// fields for a captured request, applying the mime allowlist and redaction hook.
+func bodyLogFields(cfg BodyLogConfig, reqBody *bytes.Buffer, respContentType string, respBody *bytes.Buffer) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	if reqBody != nil && reqBody.Len() > 0 {
+		value := reqBody.Bytes()
+		if cfg.Redact != nil {
+			value = cfg.Redact("http.request.body.content", value)
+		}
+		fields["http.request.body.content"] = string(value)
+	}
+
+	if respBody != nil && respBody.Len() > 0 && mimeAllowed(respContentType, cfg.MimeTypes) {
+		value := respBody.Bytes()
+		if cfg.Redact != nil {
+			value = cfg.Redact("http.response.body.content", value)
+		}
+		fields["http.response.body.content"] = string(value)
+	}
+
+	return fields
+}
@@ -0,0 +1,136 @@
+package gin_logrus
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Schema selects the field naming convention used when building log fields.
+type Schema int
+
+const (
+	// SchemaECS emits Elastic Common Schema field names. This is the default.
+	SchemaECS Schema = iota
+	// SchemaOTel emits OpenTelemetry semantic convention field names.
+	SchemaOTel
+	// SchemaFlat emits a flat, non-namespaced field set.
+	SchemaFlat
+)
+
+// FieldExtractor lets callers contribute additional log fields derived from
+// the request context, e.g. tenant/organisation IDs, request IDs or trace
+// baggage that this package has no knowledge of. Extractors are merged into
+// the log fields after the schema's own fields have been generated, so they
+// can also be used to override a schema field.
+type FieldExtractor func(c *gin.Context) logrus.Fields
+
+// DisableLogFunc decides whether a request should be skipped entirely, e.g.
+// to silence noisy health-check endpoints.
+type DisableLogFunc func(statusCode int, c *gin.Context) bool
+
+// LevelFunc decides the logrus.Level a completed request should be logged
+// at. The default routes 5xx responses to Error, 4xx to Warn, and
+// everything else to Info.
+type LevelFunc func(c *gin.Context) logrus.Level
+
+// MessageFunc builds the log message for a completed request, e.g. to
+// produce an Apache/combined-style line instead of the package default.
+type MessageFunc func(c *gin.Context) string
+
+// defaultLevelFunc routes 5xx responses to Error, 4xx to Warn, and
+// everything else to Info.
+func defaultLevelFunc(c *gin.Context) logrus.Level {
+	switch status := c.Writer.Status(); {
+	case status >= 500:
+		return logrus.ErrorLevel
+	case status >= 400:
+		return logrus.WarnLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// Config controls how GinLogrus and RecoveryWithLoggerMiddleware build their
+// log entries.
+type Config struct {
+	// Schema selects the field naming convention. Defaults to SchemaECS.
+	Schema Schema
+
+	// FieldExtractors are invoked, in order, for every request and merged
+	// into the generated log fields.
+	FieldExtractors []FieldExtractor
+
+	// DisableLog, when non-nil, is called once the request has completed
+	// and suppresses logging for requests it returns true for.
+	DisableLog DisableLogFunc
+
+	// BodyLog configures opt-in request/response body capture. Body
+	// capture is off by default.
+	BodyLog BodyLogConfig
+
+	// LevelFunc decides the log level for a completed request. Defaults to
+	// defaultLevelFunc (5xx→Error, 4xx→Warn, else Info).
+	LevelFunc LevelFunc
+
+	// MessageFunc builds the log message for a completed request. Defaults
+	// to the package's built-in messages.
+	MessageFunc MessageFunc
+
+	// SlowRequestThreshold, when non-zero, bumps the log level to Warn and
+	// adds an event.slow=true field for requests whose latency exceeds it.
+	SlowRequestThreshold time.Duration
+
+	// RequestIDHeader is the header GinLogrus reads an inbound request ID
+	// from, and echoes it back on. A UUID is generated when the header is
+	// absent. Defaults to "X-Request-ID".
+	RequestIDHeader string
+
+	// UserContextKey is the gin context key GinLogrus looks up a
+	// *models.UserContext under (typically set by an auth middleware
+	// upstream of GinLogrus) to populate the user.id field. Defaults to
+	// "userContext". The legacy UserClaimsKey map is still consulted as a
+	// fallback if no *models.UserContext is found under this key.
+	UserContextKey string
+}
+
+// defaultRequestIDHeader is used when Config.RequestIDHeader is empty.
+const defaultRequestIDHeader = "X-Request-ID"
+
+// defaultUserContextKey is used when Config.UserContextKey is empty.
+const defaultUserContextKey = "userContext"
+
+// DefaultConfig returns the Config used when none is supplied.
+func DefaultConfig() Config {
+	return Config{Schema: SchemaECS, RequestIDHeader: defaultRequestIDHeader, UserContextKey: defaultUserContextKey}
+}
+
+// PanicHandlerFunc lets applications forward a recovered panic to Sentry, a
+// custom alert path, etc. before the 500 response is written.
+type PanicHandlerFunc func(c *gin.Context, err interface{}, frames []Frame)
+
+// RecoveryConfig controls how RecoveryWithLoggerMiddleware collects and
+// reports the stack trace of a recovered panic.
+type RecoveryConfig struct {
+	// MaxFrames caps how many stack frames are collected. Defaults to 32.
+	MaxFrames int
+
+	// SkipFrames skips this many additional innermost frames before
+	// collection starts. The frames belonging to runtime.Callers,
+	// collectTrace and the recovery middleware's own deferred closure are
+	// always skipped regardless of this value; SkipFrames is for frames
+	// the caller introduces on top of that, e.g. a shared panic-handling
+	// wrapper of its own.
+	SkipFrames int
+
+	// PanicHandler, when non-nil, is called with the recovered panic and
+	// its collected frames before the 500 response is written.
+	PanicHandler PanicHandlerFunc
+}
+
+// DefaultRecoveryConfig returns the RecoveryConfig used when none is
+// supplied.
+func DefaultRecoveryConfig() RecoveryConfig {
+	return RecoveryConfig{MaxFrames: defaultMaxFrames}
+}
@@ -0,0 +1,32 @@
+package gin_logrus
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jamesbuddrige/gin-logrus/models"
+)
+
+// UserContextExtractor returns a FieldExtractor that reads a *models.UserContext
+// stored in the gin context under key (typically set by an auth middleware
+// upstream of GinLogrus) and adds its user, tenant and organisation IDs to
+// the log fields.
+func UserContextExtractor(key string) FieldExtractor {
+	return func(c *gin.Context) logrus.Fields {
+		value, ok := c.Get(key)
+		if !ok {
+			return nil
+		}
+
+		user, ok := value.(*models.UserContext)
+		if !ok {
+			return nil
+		}
+
+		return logrus.Fields{
+			"user.id":                user.UserID.String(),
+			"organisation.id":        user.OrganisationID.String(),
+			"organisation.tenant.id": user.TenantID.String(),
+		}
+	}
+}
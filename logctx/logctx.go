@@ -0,0 +1,30 @@
+// Package logctx carries a request-scoped *logrus.Entry through a
+// context.Context, so handlers and downstream calls can log with the same
+// correlation fields (trace ID, request ID, user ID, ...) that GinLogrus
+// attached at the start of the request.
+package logctx
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+var entryKey = contextKey{}
+
+// WithLogger returns a copy of ctx carrying entry, retrievable with
+// FromContext.
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey, entry)
+}
+
+// FromContext returns the *logrus.Entry carried by ctx, or logrus.NewEntry
+// with the standard logger if ctx carries none.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
@@ -1,71 +1,140 @@
 package gin_logrus
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
-	"runtime"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jamesbuddrige/gin-logrus/logctx"
+	"github.com/jamesbuddrige/gin-logrus/models"
 )
 
 // UserClaimsKey is the key for user claims in context
 const UserClaimsKey = "userClaims"
 
+// LoggerContextKey is the gin context key GinLogrus stores the
+// request-scoped *logrus.Entry under, alongside installing it into
+// c.Request.Context() via logctx.WithLogger.
+const LoggerContextKey = "logger"
+
+// extractedFieldsContextKey is the gin context key used to cache the result
+// of running cfg.FieldExtractors, so a request that builds two log entries
+// (the start-of-request entry and the end-of-request one) only runs them
+// once.
+const extractedFieldsContextKey = "gin_logrus.extractedFields"
+
 // GinLogrus is a middleware function that uses Logrus logger instead of the default Gin logger.
-func GinLogrus(logger *logrus.Logger) gin.HandlerFunc {
+func GinLogrus(logger *logrus.Logger, cfg Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Start timer
 		start := time.Now()
 
+		requestIDHeader := cfg.RequestIDHeader
+		if requestIDHeader == "" {
+			requestIDHeader = defaultRequestIDHeader
+		}
+
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Writer.Header().Set(requestIDHeader, requestID)
+
+		// Install a request-scoped logger, pre-populated with correlation
+		// fields, before the handler chain runs so handlers can log via
+		// logctx.FromContext(c.Request.Context()) with automatic correlation.
+		entry := requestEntry(logger, c, cfg, requestID)
+		c.Request = c.Request.WithContext(logctx.WithLogger(c.Request.Context(), entry))
+		c.Set(LoggerContextKey, entry)
+
+		var reqBody, respBody *bytes.Buffer
+		if cfg.BodyLog.Enabled {
+			reqBody = captureRequestBody(c, cfg.BodyLog)
+			respBody = captureResponseWriter(c, cfg.BodyLog).body
+		}
+
 		// Process request
 		c.Next()
 
+		latency := time.Since(start)
+		addRequestSpanEvent(c.Request.Context(), c.Writer.Status(), latency)
+
+		if cfg.DisableLog != nil && cfg.DisableLog(c.Writer.Status(), c) {
+			return
+		}
+
 		// Generate log fields
-		fields := generateLogFields(c, start)
+		fields := generateLogFields(c, start, cfg)
+		fields["event.request_id"] = requestID
+
+		if cfg.BodyLog.Enabled {
+			for k, v := range bodyLogFields(cfg.BodyLog, reqBody, c.Writer.Header().Get("Content-Type"), respBody) {
+				fields[k] = v
+			}
+		}
+
+		level := defaultLevelFunc(c)
+		if cfg.LevelFunc != nil {
+			level = cfg.LevelFunc(c)
+		}
+
+		if cfg.SlowRequestThreshold > 0 && latency > cfg.SlowRequestThreshold {
+			if level > logrus.WarnLevel {
+				// Logrus levels are ordered most- to least-severe, so only
+				// raise the level if it wasn't already more severe than Warn.
+				level = logrus.WarnLevel
+			}
+			fields["event.slow"] = true
+		}
 
 		// Create log entry
-		entry := logger.WithFields(fields)
+		entry = logger.WithFields(fields)
 
 		// If user exists in context, add user ID to log entry.
-		if user, ok := c.Get(UserClaimsKey); ok {
-			if userMap, ok := user.(map[string]interface{}); ok {
-				if userID, exists := userMap["UserID"].(string); exists {
-					entry = entry.WithContext(c.Request.Context()).WithField("user.id", userID)
-				}
-			}
+		if id, ok := userID(c, cfg); ok {
+			entry = entry.WithContext(c.Request.Context()).WithField("user.id", id)
 		}
 
+		message := "Request processed successfully"
 		if len(c.Errors) > 0 {
-			// Append error field if this is an erroneous request.
-			entry.Errorf("Request failed: %v", c.Errors.String())
-		} else {
-			entry.Info("Request processed successfully")
+			message = fmt.Sprintf("Request failed: %v", c.Errors.String())
+		}
+		if cfg.MessageFunc != nil {
+			message = cfg.MessageFunc(c)
 		}
+
+		entry.Log(level, message)
 	}
 }
 
-func RecoveryWithLoggerMiddleware(logger *logrus.Logger) gin.HandlerFunc {
+func RecoveryWithLoggerMiddleware(logger *logrus.Logger, cfg Config, recoveryCfg RecoveryConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
 				start := time.Now()
-				// Capture the stack trace
-				stack := make([]byte, 2048)
-				stack = stack[:runtime.Stack(stack, false)]
+
+				// Capture a structured stack trace
+				frames, joined := collectTrace(recoveryCfg.SkipFrames, recoveryCfg.MaxFrames)
 
 				// Generate log fields
-				fields := generateLogFields(c, start)
+				fields := generateLogFields(c, start, cfg)
 
 				// Create log entry
 				entry := logger.WithFields(fields)
 
 				// Log as a single entry
 				entry.WithFields(logrus.Fields{
-					"error.message":     err,
-					"error.stack_trace": string(stack),
+					"error.message":            err,
+					"error.stack_trace":        joined,
+					"error.stack_trace.frames": frames,
 				}).Error("A panic occurred")
 
 				span := trace.SpanFromContext(c.Request.Context())
@@ -73,6 +142,16 @@ func RecoveryWithLoggerMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 				if span.SpanContext().IsValid() {
 					// Set outcome
 					span.SetStatus(codes.Error, "panic occurred")
+
+					if recoveredErr, ok := err.(error); ok {
+						span.RecordError(recoveredErr, trace.WithAttributes(
+							attribute.String("error.stack_trace", joined),
+						))
+					}
+				}
+
+				if recoveryCfg.PanicHandler != nil {
+					recoveryCfg.PanicHandler(c, err, frames)
 				}
 
 				// Optionally, you can write a response to the client
@@ -85,46 +164,89 @@ func RecoveryWithLoggerMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 	}
 }
 
-func generateLogFields(c *gin.Context, start time.Time) logrus.Fields {
-	// Calculate latency
-	latency := time.Since(start)
-
-	fields := logrus.Fields{
-		"url.domain":                c.Request.Host,
-		"url.fragment":              c.Request.URL.Fragment,
-		"url.full":                  c.Request.URL.String(),
-		"url.original":              c.Request.URL.String(),
-		"url.path":                  c.Request.URL.Path,
-		"url.port":                  c.Request.URL.Port(),
-		"url.query":                 c.Request.URL.RawQuery,
-		"url.registered_domain":     c.Request.URL.Hostname(),
-		"url.scheme":                c.Request.URL.Scheme,
-		"http.request.bytes":        c.Request.ContentLength,
-		"http.request.method":       c.Request.Method,
-		"http.request.mime_type":    c.ContentType(),
-		"http.request.referrer":     c.Request.Referer(),
-		"http.response.body.bytes":  c.Writer.Size(),
-		"http.response.status_code": c.Writer.Status(),
-		"http.version":              c.Request.Proto,
-		"client.address":            c.ClientIP(),
-		"client.ip":                 c.ClientIP(),
-		"server.address":            c.Request.Host,
-		"server.ip":                 c.Request.Host,
-		"user_agent.original":       c.Request.UserAgent(),
-		"event.duration":            latency.Seconds(),
-		"event.start":               start.Format("2006-01-02T15:04:05.000Z"),
-		"event.end":                 time.Now().Format("2006-01-02T15:04:05.000Z"),
+func generateLogFields(c *gin.Context, start time.Time, cfg Config) logrus.Fields {
+	fields := buildSchemaFields(c, start, cfg.Schema)
+
+	for k, v := range traceFields(c.Request.Context()) {
+		fields[k] = v
+	}
+
+	if id, ok := userID(c, cfg); ok {
+		fields["user.id"] = id
+	}
+
+	for k, v := range extractedFields(c, cfg) {
+		fields[k] = v
+	}
+
+	return fields
+}
+
+// requestEntry builds the *logrus.Entry installed into the request context
+// at the start of the request, pre-populated with trace, user and request
+// ID correlation fields.
+func requestEntry(logger *logrus.Logger, c *gin.Context, cfg Config, requestID string) *logrus.Entry {
+	fields := logrus.Fields{"event.request_id": requestID}
+
+	for k, v := range traceFields(c.Request.Context()) {
+		fields[k] = v
+	}
+
+	if id, ok := userID(c, cfg); ok {
+		fields["user.id"] = id
+	}
+
+	for k, v := range extractedFields(c, cfg) {
+		fields[k] = v
+	}
+
+	return logger.WithFields(fields)
+}
+
+// userID resolves the current request's user ID, preferring a
+// *models.UserContext stored under cfg.UserContextKey and falling back to
+// the legacy UserClaimsKey map for callers that haven't migrated.
+func userID(c *gin.Context, cfg Config) (string, bool) {
+	userContextKey := cfg.UserContextKey
+	if userContextKey == "" {
+		userContextKey = defaultUserContextKey
+	}
+
+	if value, ok := c.Get(userContextKey); ok {
+		if user, ok := value.(*models.UserContext); ok {
+			return user.UserID.String(), true
+		}
 	}
 
-	// If user exists in context, add user ID to fields.
 	if user, ok := c.Get(UserClaimsKey); ok {
-		// Check if it's a map with string keys and interface{} values
 		if userMap, ok := user.(map[string]interface{}); ok {
 			if userID, exists := userMap["UserID"].(string); exists {
-				fields["user.id"] = userID
+				return userID, true
 			}
 		}
 	}
 
+	return "", false
+}
+
+// extractedFields runs cfg.FieldExtractors once per request and caches the
+// result on c, so building both the start-of-request entry and the
+// end-of-request fields doesn't run extractors twice.
+func extractedFields(c *gin.Context, cfg Config) logrus.Fields {
+	if cached, ok := c.Get(extractedFieldsContextKey); ok {
+		if fields, ok := cached.(logrus.Fields); ok {
+			return fields
+		}
+	}
+
+	fields := logrus.Fields{}
+	for _, extract := range cfg.FieldExtractors {
+		for k, v := range extract(c) {
+			fields[k] = v
+		}
+	}
+
+	c.Set(extractedFieldsContextKey, fields)
+
 	return fields
 }
@@ -0,0 +1,192 @@
+package gin_logrus
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func newTestRouter(logger *logrus.Logger, cfg Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GinLogrus(logger, cfg))
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+	r.POST("/echo", func(c *gin.Context) {
+		// Read the body so the tee reader installed by body capture
+		// actually has something to capture.
+		io.ReadAll(c.Request.Body)
+		c.Status(http.StatusCreated)
+	})
+	return r
+}
+
+func TestGinLogrus_SchemaSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+		field  string
+	}{
+		{"ECS", SchemaECS, "url.path"},
+		{"OTel", SchemaOTel, "http.target"},
+		{"Flat", SchemaFlat, "path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := logrustest.NewNullLogger()
+			router := newTestRouter(logger, Config{Schema: tt.schema})
+
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			entry := hook.LastEntry()
+			if entry == nil {
+				t.Fatal("expected a log entry to be emitted")
+			}
+			if _, ok := entry.Data[tt.field]; !ok {
+				t.Errorf("expected field %q in log entry, got fields %v", tt.field, entry.Data)
+			}
+		})
+	}
+}
+
+func TestGinLogrus_BodyCaptureRedaction(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+
+	cfg := Config{
+		Schema: SchemaECS,
+		BodyLog: BodyLogConfig{
+			Enabled:   true,
+			MimeTypes: []string{"application/json"},
+			Redact: func(field string, value []byte) []byte {
+				return bytes.ReplaceAll(value, []byte("secret"), []byte("[REDACTED]"))
+			},
+		},
+	}
+	router := newTestRouter(logger, cfg)
+
+	body := bytes.NewBufferString(`{"password":"secret"}`)
+	req := httptest.NewRequest(http.MethodPost, "/echo", body)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("expected a log entry to be emitted")
+	}
+
+	captured, ok := entry.Data["http.request.body.content"].(string)
+	if !ok {
+		t.Fatalf("expected http.request.body.content field, got fields %v", entry.Data)
+	}
+	if bytes.Contains([]byte(captured), []byte("secret")) {
+		t.Errorf("expected redaction to strip %q, got %q", "secret", captured)
+	}
+	if !bytes.Contains([]byte(captured), []byte("[REDACTED]")) {
+		t.Errorf("expected redacted placeholder in captured body, got %q", captured)
+	}
+}
+
+func TestGinLogrus_RequestIDEcho(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+	router := newTestRouter(logger, Config{Schema: SchemaECS})
+
+	t.Run("generates a request ID when absent", func(t *testing.T) {
+		hook.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(defaultRequestIDHeader)
+		if id == "" {
+			t.Fatal("expected a generated request ID on the response")
+		}
+
+		entry := hook.LastEntry()
+		if entry == nil || entry.Data["event.request_id"] != id {
+			t.Errorf("expected logged event.request_id to match echoed header %q, got %v", id, entry.Data["event.request_id"])
+		}
+	})
+
+	t.Run("echoes an inbound request ID", func(t *testing.T) {
+		hook.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set(defaultRequestIDHeader, "fixed-id")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(defaultRequestIDHeader); got != "fixed-id" {
+			t.Errorf("expected request ID to be echoed back as %q, got %q", "fixed-id", got)
+		}
+	})
+}
+
+func TestGinLogrus_FieldExtractorsRunOncePerRequest(t *testing.T) {
+	logger, _ := logrustest.NewNullLogger()
+
+	var calls int
+	cfg := Config{
+		Schema: SchemaECS,
+		FieldExtractors: []FieldExtractor{
+			func(c *gin.Context) logrus.Fields {
+				calls++
+				return nil
+			},
+		},
+	}
+	router := newTestRouter(logger, cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if calls != 1 {
+		t.Errorf("expected FieldExtractors to run once per request, ran %d times", calls)
+	}
+}
+
+func TestRecoveryWithLoggerMiddleware_StackTraceSkipsOwnFrames(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RecoveryWithLoggerMiddleware(logger, Config{Schema: SchemaECS}, RecoveryConfig{}))
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("expected a log entry to be emitted")
+	}
+
+	frames, ok := entry.Data["error.stack_trace.frames"].([]Frame)
+	if !ok || len(frames) == 0 {
+		t.Fatalf("expected captured stack frames, got %v", entry.Data["error.stack_trace.frames"])
+	}
+
+	// collectTrace and the deferred closure that calls it (both living in
+	// this package's own files) should never show up: those are the frames
+	// skipFrames+3 is meant to remove. Frames for the outer handler closure
+	// that calls c.Next() are a legitimate part of the call chain (just like
+	// any other caller further up the stack) and are expected to remain.
+	for _, f := range frames {
+		if strings.Contains(f.Function, "collectTrace") {
+			t.Errorf("expected collectTrace's own frame to be skipped, found %q", f.Function)
+		}
+	}
+	if frames[0].Function != "runtime.gopanic" {
+		t.Errorf("expected the innermost captured frame to be the panic site, got %q", frames[0].Function)
+	}
+}
@@ -0,0 +1,88 @@
+package gin_logrus
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// buildSchemaFields generates the base log fields for a completed request
+// according to the configured Schema.
+func buildSchemaFields(c *gin.Context, start time.Time, schema Schema) logrus.Fields {
+	switch schema {
+	case SchemaOTel:
+		return buildOTelFields(c, start)
+	case SchemaFlat:
+		return buildFlatFields(c, start)
+	default:
+		return buildECSFields(c, start)
+	}
+}
+
+// buildECSFields emits Elastic Common Schema field names.
+func buildECSFields(c *gin.Context, start time.Time) logrus.Fields {
+	latency := time.Since(start)
+
+	return logrus.Fields{
+		"url.domain":                c.Request.Host,
+		"url.fragment":              c.Request.URL.Fragment,
+		"url.full":                  c.Request.URL.String(),
+		"url.original":              c.Request.URL.String(),
+		"url.path":                  c.Request.URL.Path,
+		"url.port":                  c.Request.URL.Port(),
+		"url.query":                 c.Request.URL.RawQuery,
+		"url.registered_domain":     c.Request.URL.Hostname(),
+		"url.scheme":                c.Request.URL.Scheme,
+		"http.request.bytes":        c.Request.ContentLength,
+		"http.request.method":       c.Request.Method,
+		"http.request.mime_type":    c.ContentType(),
+		"http.request.referrer":     c.Request.Referer(),
+		"http.response.body.bytes":  c.Writer.Size(),
+		"http.response.status_code": c.Writer.Status(),
+		"http.version":              c.Request.Proto,
+		"client.address":            c.ClientIP(),
+		"client.ip":                 c.ClientIP(),
+		"server.address":            c.Request.Host,
+		"server.ip":                 c.Request.Host,
+		"user_agent.original":       c.Request.UserAgent(),
+		"event.duration":            latency.Seconds(),
+		"event.start":               start.Format("2006-01-02T15:04:05.000Z"),
+		"event.end":                 time.Now().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// buildOTelFields emits OpenTelemetry HTTP semantic convention field names.
+func buildOTelFields(c *gin.Context, start time.Time) logrus.Fields {
+	latency := time.Since(start)
+
+	return logrus.Fields{
+		"http.method":                  c.Request.Method,
+		"http.target":                  c.Request.URL.Path,
+		"http.host":                    c.Request.Host,
+		"http.scheme":                  c.Request.URL.Scheme,
+		"http.flavor":                  c.Request.Proto,
+		"http.user_agent":              c.Request.UserAgent(),
+		"http.request_content_length":  c.Request.ContentLength,
+		"http.response_content_length": c.Writer.Size(),
+		"http.status_code":             c.Writer.Status(),
+		"net.peer.ip":                  c.ClientIP(),
+		"duration_ms":                  latency.Milliseconds(),
+	}
+}
+
+// buildFlatFields emits a flat, non-namespaced field set.
+func buildFlatFields(c *gin.Context, start time.Time) logrus.Fields {
+	latency := time.Since(start)
+
+	return logrus.Fields{
+		"method":     c.Request.Method,
+		"path":       c.Request.URL.Path,
+		"status":     c.Writer.Status(),
+		"client_ip":  c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+		"bytes_in":   c.Request.ContentLength,
+		"bytes_out":  c.Writer.Size(),
+		"latency_ms": latency.Milliseconds(),
+	}
+}
@@ -0,0 +1,57 @@
+package gin_logrus
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Frame describes a single stack frame captured during a panic.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// defaultMaxFrames bounds how many frames are collected when
+// RecoveryConfig.MaxFrames is left at its zero value.
+const defaultMaxFrames = 32
+
+// collectTrace walks the goroutine's call stack, skipping skipFrames frames
+// on top of the ones collectTrace always skips for its caller, and returns
+// up to maxFrames structured Frames plus a human-readable, newline-joined
+// rendering of the same frames.
+func collectTrace(skipFrames, maxFrames int) (frames []Frame, joined string) {
+	if maxFrames <= 0 {
+		maxFrames = defaultMaxFrames
+	}
+
+	pcs := make([]uintptr, maxFrames)
+	// +3 to skip runtime.Callers, collectTrace itself, and the recovery
+	// middleware's own deferred closure that calls it, so skipFrames only
+	// needs to account for frames the caller introduces beyond that.
+	n := runtime.Callers(skipFrames+3, pcs)
+	if n == 0 {
+		return nil, ""
+	}
+
+	callersFrames := runtime.CallersFrames(pcs[:n])
+
+	var lines []string
+	for {
+		frame, more := callersFrames.Next()
+
+		frames = append(frames, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	return frames, strings.Join(lines, "\n")
+}
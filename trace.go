@@ -0,0 +1,41 @@
+package gin_logrus
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceFields reads the active trace.SpanContext from ctx and returns the
+// ECS-compatible log↔trace correlation fields for it. It returns nil if
+// there is no valid span in ctx.
+func traceFields(ctx context.Context) logrus.Fields {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	return logrus.Fields{
+		"trace.id":    spanCtx.TraceID().String(),
+		"span.id":     spanCtx.SpanID().String(),
+		"trace.flags": spanCtx.TraceFlags().String(),
+	}
+}
+
+// addRequestSpanEvent records an "http.request" event on the active span
+// with the outcome of the request, so log entries and traces correlate in
+// tools like Tempo or Jaeger without additional middleware.
+func addRequestSpanEvent(ctx context.Context, statusCode int, latency time.Duration) {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return
+	}
+
+	span.AddEvent("http.request", trace.WithAttributes(
+		attribute.Int("http.status_code", statusCode),
+		attribute.Int64("http.duration_ms", latency.Milliseconds()),
+	))
+}